@@ -0,0 +1,14 @@
+// Package events defines the config-change events emitted by servconf so a
+// web UI can render live updates and a central audit log can record them.
+package events
+
+import "time"
+
+// Event records a single field change on a ServerConfig.
+type Event struct {
+	Username  string
+	Field     string
+	OldValue  string
+	NewValue  string
+	Timestamp time.Time
+}
@@ -0,0 +1,139 @@
+// Package policy defines the validation and quota rules a servconf.ServerConfig
+// must satisfy before it is handed to the backend.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Range is an inclusive min/max bound on a resource.Quantity. A nil Min or
+// Max means that side is unbounded, so a Range zero value (as loaded from a
+// policy YAML that omits the field) imposes no restriction.
+type Range struct {
+	Min *resource.Quantity `yaml:"min"`
+	Max *resource.Quantity `yaml:"max"`
+}
+
+// Contains reports whether q falls within the inclusive bounds of r.
+func (r Range) Contains(q resource.Quantity) bool {
+	if r.Min != nil && q.Cmp(*r.Min) < 0 {
+		return false
+	}
+	if r.Max != nil && q.Cmp(*r.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// PortRange is an inclusive min/max bound on a port number. A nil Min or Max
+// means that side is unbounded, so a PortRange zero value (as loaded from a
+// policy YAML that omits the field) imposes no restriction.
+type PortRange struct {
+	Min *int32 `yaml:"min"`
+	Max *int32 `yaml:"max"`
+}
+
+// Contains reports whether port falls within the inclusive bounds of r.
+func (r PortRange) Contains(port int32) bool {
+	if r.Min != nil && port < *r.Min {
+		return false
+	}
+	if r.Max != nil && port > *r.Max {
+		return false
+	}
+	return true
+}
+
+// Policy is the set of resource bounds, allow-lists and quotas enforced
+// against a ServerConfig. Load one with LoadPolicy.
+type Policy struct {
+	CPU  Range `yaml:"cpu"`
+	RAM  Range `yaml:"ram"`
+	Disk Range `yaml:"disk"`
+
+	AllowedServerTypes []string         `yaml:"allowedServerTypes"`
+	AllowedProtocols   []apiv1.Protocol `yaml:"allowedProtocols"`
+
+	InternalPorts PortRange `yaml:"internalPorts"`
+	ExternalPorts PortRange `yaml:"externalPorts"`
+
+	// MaxServersPerUser caps how many pods labeled with a given username
+	// may be running concurrently. Zero means unlimited.
+	MaxServersPerUser int `yaml:"maxServersPerUser"`
+
+	// AllowGPU controls whether a ServerConfig may request GPUs at all.
+	AllowGPU bool `yaml:"allowGPU"`
+}
+
+// LoadPolicy reads and parses a Policy from a YAML file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: read %s: %w", path, err)
+	}
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("policy: parse %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// AllowsServerType reports whether serverType is in the allow-list, or
+// whether the allow-list is empty (meaning no restriction is configured).
+func (p *Policy) AllowsServerType(serverType string) bool {
+	if len(p.AllowedServerTypes) == 0 {
+		return true
+	}
+	for _, t := range p.AllowedServerTypes {
+		if t == serverType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsProtocol reports whether protocol is in the allow-list, or whether
+// the allow-list is empty (meaning no restriction is configured).
+func (p *Policy) AllowsProtocol(protocol apiv1.Protocol) bool {
+	if len(p.AllowedProtocols) == 0 {
+		return true
+	}
+	for _, proto := range p.AllowedProtocols {
+		if proto == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsGPU reports whether a ServerConfig may request GPUs under p.
+func (p *Policy) AllowsGPU() bool {
+	return p.AllowGPU
+}
+
+// CheckUserQuota lists pods labeled "username=<username>" via clientset and
+// returns an error if username has reached MaxServersPerUser concurrent
+// servers. A MaxServersPerUser of zero disables the check.
+func (p *Policy) CheckUserQuota(ctx context.Context, clientset kubernetes.Interface, username string) error {
+	if p.MaxServersPerUser <= 0 {
+		return nil
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("username=%s", username),
+	})
+	if err != nil {
+		return fmt.Errorf("policy: list pods for user %s: %w", username, err)
+	}
+	if len(pods.Items) >= p.MaxServersPerUser {
+		return fmt.Errorf("policy: user %s has reached the max of %d concurrent servers", username, p.MaxServersPerUser)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestLoadPolicyPartialIsUnbounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	data := []byte("allowGPU: false\nmaxServersPerUser: 5\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write policy file: %v", err)
+	}
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+
+	if !p.CPU.Contains(resource.MustParse("4")) {
+		t.Error("CPU.Contains(4) = false, want true for a range omitted from the policy")
+	}
+	if !p.RAM.Contains(resource.MustParse("16Gi")) {
+		t.Error("RAM.Contains(16Gi) = false, want true for a range omitted from the policy")
+	}
+	if !p.Disk.Contains(resource.MustParse("100Gi")) {
+		t.Error("Disk.Contains(100Gi) = false, want true for a range omitted from the policy")
+	}
+	if !p.InternalPorts.Contains(8080) {
+		t.Error("InternalPorts.Contains(8080) = false, want true for a range omitted from the policy")
+	}
+	if !p.ExternalPorts.Contains(30080) {
+		t.Error("ExternalPorts.Contains(30080) = false, want true for a range omitted from the policy")
+	}
+}
+
+func TestRangeContainsRespectsExplicitBounds(t *testing.T) {
+	min := resource.MustParse("1")
+	max := resource.MustParse("8")
+	r := Range{Min: &min, Max: &max}
+
+	if r.Contains(resource.MustParse("0.5")) {
+		t.Error("Contains(0.5) = true, want false (below Min)")
+	}
+	if !r.Contains(resource.MustParse("4")) {
+		t.Error("Contains(4) = false, want true (within bounds)")
+	}
+	if r.Contains(resource.MustParse("16")) {
+		t.Error("Contains(16) = true, want false (above Max)")
+	}
+}
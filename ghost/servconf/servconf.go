@@ -2,20 +2,29 @@ package servconf
 
 import (
 	"bytes"
+	"context"
 	"errors"
-	"flag"
+	"fmt"
+	"log/slog"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
-)
 
-var kubeconfig *string
+	"github.com/Quinn-5/learning-go/ghost/servconf/events"
+	"github.com/Quinn-5/learning-go/ghost/servconf/policy"
+)
 
 // Server config with private values
 // for use in backend functions.
@@ -52,8 +61,71 @@ type ServerConfig struct {
 	// Protocol used for communication
 	protocol apiv1.Protocol
 
+	// Number of GPUs to assign
+	gpuCount int
+
+	// Device-plugin vendor owning the GPU resource, e.g. "nvidia.com"
+	gpuVendor string
+
+	// CPU architecture to schedule onto, e.g. "amd64"
+	arch string
+
+	// Labels a node must have for this server to be scheduled onto it
+	nodeSelector map[string]string
+
+	// Taints this server tolerates
+	tolerations []apiv1.Toleration
+
+	// Persistent volumes attached to this server
+	volumes []Volume
+
 	// kubeconfig
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
+
+	// Active quota/validation policy, if any. Nil means unrestricted.
+	policy *policy.Policy
+
+	// Context backend calls made on behalf of cfg should respect.
+	ctx context.Context
+
+	// Structured logger config lifecycle events are written to.
+	log *slog.Logger
+
+	// Guards watchers.
+	mu sync.Mutex
+
+	// Channels subscribed via Watch.
+	watchers []chan events.Event
+}
+
+// ValidationErrors aggregates every policy violation found by Validate.
+type ValidationErrors []error
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, err := range ve {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Volume is a persistent volume claim attached to a server, kept separate
+// from the server's ephemeral scratch disk.
+type Volume struct {
+	Name         string
+	Size         resource.Quantity
+	StorageClass string
+	AccessMode   apiv1.PersistentVolumeAccessMode
+	MountPath    string
+}
+
+// WebVolume is the web-facing representation of a Volume.
+type WebVolume struct {
+	Name         string
+	Size         string
+	StorageClass string
+	AccessMode   string
+	MountPath    string
 }
 
 // Server config with public values
@@ -69,32 +141,199 @@ type WebConfig struct {
 	IP           string
 	InternalPort int32
 	ExternalPort int32
+	GPU          string
+	Arch         string
+	NodeSelector string
+	Tolerations  string
+	Volumes      []WebVolume
+}
+
+// options collects the values Option functions configure on a future
+// ServerConfig before it exists.
+type options struct {
+	kubeconfigPath string
+	restConfig     *rest.Config
+	clientset      kubernetes.Interface
+	ctx            context.Context
+	logger         *slog.Logger
+}
+
+// Option configures optional behavior of New.
+type Option func(*options)
+
+// WithKubeconfig sets an explicit path to a kubeconfig file to use when not
+// running in-cluster. It takes precedence over $KUBECONFIG and ~/.kube/config.
+func WithKubeconfig(path string) Option {
+	return func(o *options) {
+		o.kubeconfigPath = path
+	}
 }
 
-func New(username string, serverName string) *ServerConfig {
-	cfg := &ServerConfig{}
+// WithRESTConfig supplies a ready-made *rest.Config, skipping kubeconfig
+// discovery entirely.
+func WithRESTConfig(restConfig *rest.Config) Option {
+	return func(o *options) {
+		o.restConfig = restConfig
+	}
+}
 
-	if kubeconfig == nil {
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-		} else {
-			kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+// WithClientset supplies a ready-made clientset, skipping kubeconfig
+// discovery and REST config construction entirely. Primarily useful in
+// tests, where a fake clientset (k8s.io/client-go/kubernetes/fake) can be
+// injected.
+func WithClientset(clientset kubernetes.Interface) Option {
+	return func(o *options) {
+		o.clientset = clientset
+	}
+}
+
+// WithContext sets the context that backend calls made on behalf of the
+// ServerConfig should respect. Defaults to context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithLogger sets the structured logger config lifecycle events are written
+// to. Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// New builds a ServerConfig for username/serverName. A clientset from
+// WithClientset, or else a REST config from WithRESTConfig, is used as given.
+// Without either, kubeconfig discovery tries, in order: an in-cluster config,
+// WithKubeconfig, $KUBECONFIG, then ~/.kube/config.
+func New(username string, serverName string, opts ...Option) (*ServerConfig, error) {
+	o := &options{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := &ServerConfig{ctx: o.ctx, log: o.logger}
+
+	clientset := o.clientset
+	if clientset == nil {
+		restConfig := o.restConfig
+		if restConfig == nil {
+			var err error
+			restConfig, err = resolveRESTConfig(o.kubeconfigPath)
+			if err != nil {
+				return nil, fmt.Errorf("servconf: resolve kubeconfig: %w", err)
+			}
+		}
+		var err error
+		clientset, err = kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("servconf: build clientset: %w", err)
 		}
-		flag.Parse()
 	}
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
-	if err != nil {
-		panic(err)
+	cfg.clientset = clientset
+
+	if err := cfg.setUsername(username); err != nil {
+		return nil, err
 	}
-	cfg.clientset, err = kubernetes.NewForConfig(config)
-	if err != nil {
-		panic(err)
+	if err := cfg.setServerName(serverName); err != nil {
+		return nil, err
+	}
+
+	cfg.emitLifecycle("New")
+	return cfg, nil
+}
+
+// resolveRESTConfig discovers a *rest.Config without relying on package-level
+// flags: in-cluster config first, then explicitPath, then $KUBECONFIG, then
+// ~/.kube/config. explicitPath is checked before $KUBECONFIG because a
+// caller that passed WithKubeconfig explicitly wants that file honored over
+// whatever happens to be set in the environment.
+func resolveRESTConfig(explicitPath string) (*rest.Config, error) {
+	if restConfig, err := rest.InClusterConfig(); err == nil {
+		return restConfig, nil
+	}
+	if explicitPath != "" {
+		return clientcmd.BuildConfigFromFlags("", explicitPath)
+	}
+	if envPath := os.Getenv("KUBECONFIG"); envPath != "" {
+		return clientcmd.BuildConfigFromFlags("", envPath)
+	}
+	if home := homedir.HomeDir(); home != "" {
+		return clientcmd.BuildConfigFromFlags("", filepath.Join(home, ".kube", "config"))
+	}
+	return nil, errors.New("servconf: no kubeconfig found: not running in-cluster, $KUBECONFIG is unset and no home directory was found")
+}
+
+// GetContext returns the context backend calls made on behalf of cfg should
+// respect.
+func (cfg *ServerConfig) GetContext() context.Context {
+	return cfg.ctx
+}
+
+// logger returns cfg's structured logger, falling back to slog.Default() if
+// none was set via WithLogger.
+func (cfg *ServerConfig) logger() *slog.Logger {
+	if cfg.log != nil {
+		return cfg.log
+	}
+	return slog.Default()
+}
+
+// emit logs a field change at debug level and publishes it to any active
+// Watch subscribers. A subscriber whose channel is full has the event
+// dropped rather than blocking the setter.
+func (cfg *ServerConfig) emit(field, oldValue, newValue string) {
+	ev := events.Event{
+		Username:  cfg.username,
+		Field:     field,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Timestamp: time.Now(),
+	}
+	cfg.logger().Debug("servconf field changed",
+		"username", ev.Username, "field", ev.Field, "old", ev.OldValue, "new", ev.NewValue)
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	for _, ch := range cfg.watchers {
+		select {
+		case ch <- ev:
+		default:
+		}
 	}
+}
+
+// emitLifecycle emits an event marking that cfg reached a lifecycle stage
+// such as "New", "Validate" or "WebConfig".
+func (cfg *ServerConfig) emitLifecycle(stage string) {
+	cfg.emit("lifecycle", "", stage)
+}
 
-	cfg.setUsername(username)
-	cfg.setServerName(serverName)
+// Watch returns a channel streaming config-change events for cfg so a web
+// UI can render live updates or a central audit log can record them. The
+// channel is closed once ctx is done.
+func (cfg *ServerConfig) Watch(ctx context.Context) <-chan events.Event {
+	ch := make(chan events.Event, 16)
+
+	cfg.mu.Lock()
+	cfg.watchers = append(cfg.watchers, ch)
+	cfg.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		cfg.mu.Lock()
+		defer cfg.mu.Unlock()
+		for i, w := range cfg.watchers {
+			if w == ch {
+				cfg.watchers = append(cfg.watchers[:i], cfg.watchers[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
 
-	return cfg
+	return ch
 }
 
 func (cfg *ServerConfig) GetUsername() string {
@@ -106,7 +345,9 @@ func (cfg *ServerConfig) setUsername(username string) error {
 	if !bytes.Equal(exp.Find([]byte(username)), []byte(username)) {
 		return errors.New("username must contain only alphanumeric, lowercase characters")
 	} else {
+		old := cfg.username
 		cfg.username = strings.ToLower(username)
+		cfg.emit("username", old, cfg.username)
 		return nil
 	}
 }
@@ -120,11 +361,34 @@ func (cfg *ServerConfig) setServerName(serverName string) error {
 	if !bytes.Equal(exp.Find([]byte(serverName)), []byte(serverName)) {
 		return errors.New("servername must contain only alphanumeric, lowercase characters")
 	} else {
+		old := cfg.serverName
 		cfg.serverName = strings.ToLower(serverName)
+		cfg.emit("serverName", old, cfg.serverName)
 		return nil
 	}
 }
 
+// GetPolicy returns the quota/validation policy currently enforced against
+// cfg, or nil if none is set.
+func (cfg *ServerConfig) GetPolicy() *policy.Policy {
+	return cfg.policy
+}
+
+// SetPolicy attaches a quota/validation policy to cfg. Subsequent setters
+// and Validate will enforce it.
+func (cfg *ServerConfig) SetPolicy(p *policy.Policy) {
+	old := "none"
+	if cfg.policy != nil {
+		old = "set"
+	}
+	updated := "none"
+	if p != nil {
+		updated = "set"
+	}
+	cfg.policy = p
+	cfg.emit("policy", old, updated)
+}
+
 func (cfg *ServerConfig) GetServerType() string {
 	return cfg.serverType
 }
@@ -133,26 +397,43 @@ func (cfg *ServerConfig) SetType(serverType string) error {
 	exp := regexp.MustCompile(`[a-z]([-a-z0-9]*[a-z0-9])?`)
 	if !bytes.Equal(exp.Find([]byte(serverType)), []byte(serverType)) {
 		return errors.New("servertype must contain only alphanumeric, lowercase characters")
-	} else {
-		cfg.serverType = strings.ToLower(serverType)
-		return nil
 	}
+	serverType = strings.ToLower(serverType)
+	if cfg.policy != nil && !cfg.policy.AllowsServerType(serverType) {
+		return fmt.Errorf("servertype %q is not permitted by policy", serverType)
+	}
+	old := cfg.serverType
+	cfg.serverType = serverType
+	cfg.emit("serverType", old, cfg.serverType)
+	return nil
 }
 
 func (cfg *ServerConfig) GetInternalPort() int32 {
 	return cfg.internalPort
 }
 
-func (cfg *ServerConfig) SetInternalPort(port int32) {
+func (cfg *ServerConfig) SetInternalPort(port int32) error {
+	if cfg.policy != nil && !cfg.policy.InternalPorts.Contains(port) {
+		return fmt.Errorf("internal port %d is outside the policy-allowed range", port)
+	}
+	old := cfg.internalPort
 	cfg.internalPort = port
+	cfg.emit("internalPort", fmt.Sprintf("%d", old), fmt.Sprintf("%d", cfg.internalPort))
+	return nil
 }
 
 func (cfg *ServerConfig) GetExternalPort() int32 {
 	return cfg.externalPort
 }
 
-func (cfg *ServerConfig) SetExternalPort(port int32) {
+func (cfg *ServerConfig) SetExternalPort(port int32) error {
+	if cfg.policy != nil && !cfg.policy.ExternalPorts.Contains(port) {
+		return fmt.Errorf("external port %d is outside the policy-allowed range", port)
+	}
+	old := cfg.externalPort
 	cfg.externalPort = port
+	cfg.emit("externalPort", fmt.Sprintf("%d", old), fmt.Sprintf("%d", cfg.externalPort))
+	return nil
 }
 
 func (cfg *ServerConfig) GetIP() string {
@@ -160,62 +441,313 @@ func (cfg *ServerConfig) GetIP() string {
 }
 
 func (cfg *ServerConfig) SetIP(ip string) {
+	old := cfg.ip
 	cfg.ip = ip
+	cfg.emit("ip", old, cfg.ip)
 }
 
 func (cfg *ServerConfig) GetProtocol() apiv1.Protocol {
 	return cfg.protocol
 }
 
-func (cfg *ServerConfig) SetProtocol(protocol apiv1.Protocol) {
+func (cfg *ServerConfig) SetProtocol(protocol apiv1.Protocol) error {
+	if cfg.policy != nil && !cfg.policy.AllowsProtocol(protocol) {
+		return fmt.Errorf("protocol %q is not permitted by policy", protocol)
+	}
+	old := cfg.protocol
 	cfg.protocol = protocol
+	cfg.emit("protocol", string(old), string(cfg.protocol))
+	return nil
+}
+
+// GetGPU returns the number of GPUs requested and the device-plugin vendor
+// that owns the extended resource (e.g. "nvidia.com").
+func (cfg *ServerConfig) GetGPU() (count int, vendor string) {
+	return cfg.gpuCount, cfg.gpuVendor
+}
+
+// SetGPU requests count GPUs of vendor's device-plugin resource. Consults
+// the active policy, which may forbid GPU requests entirely.
+func (cfg *ServerConfig) SetGPU(count int, vendor string) error {
+	if count < 0 {
+		return fmt.Errorf("gpu count must not be negative, got %d", count)
+	}
+	if count > 0 && vendor == "" {
+		return errors.New("gpu vendor must be set when requesting gpus")
+	}
+	if count > 0 && cfg.policy != nil && !cfg.policy.AllowsGPU() {
+		return errors.New("gpu requests are not permitted by policy")
+	}
+	old := cfg.gpuString()
+	cfg.gpuCount = count
+	cfg.gpuVendor = vendor
+	cfg.emit("gpu", old, cfg.gpuString())
+	return nil
+}
+
+// GetGPUResourceName returns the extended resource name the scheduler
+// understands for cfg's GPU vendor, e.g. "nvidia.com/gpu". Returns "" if no
+// GPU vendor is set.
+func (cfg *ServerConfig) GetGPUResourceName() apiv1.ResourceName {
+	if cfg.gpuVendor == "" {
+		return ""
+	}
+	return apiv1.ResourceName(fmt.Sprintf("%s/gpu", cfg.gpuVendor))
+}
+
+// GetGPUQuantity returns cfg's GPU request as a resource.Quantity, suitable
+// for a pod spec's resource Requests/Limits map.
+func (cfg *ServerConfig) GetGPUQuantity() resource.Quantity {
+	return *resource.NewQuantity(int64(cfg.gpuCount), resource.DecimalSI)
+}
+
+func (cfg *ServerConfig) GetArch() string {
+	return cfg.arch
+}
+
+func (cfg *ServerConfig) SetArch(arch string) {
+	old := cfg.arch
+	cfg.arch = arch
+	cfg.emit("arch", old, cfg.arch)
+}
+
+func (cfg *ServerConfig) GetNodeSelector() map[string]string {
+	return cfg.nodeSelector
+}
+
+func (cfg *ServerConfig) SetNodeSelector(selector map[string]string) {
+	old := cfg.nodeSelectorString()
+	cfg.nodeSelector = selector
+	cfg.emit("nodeSelector", old, cfg.nodeSelectorString())
+}
+
+func (cfg *ServerConfig) GetTolerations() []apiv1.Toleration {
+	return cfg.tolerations
+}
+
+func (cfg *ServerConfig) SetTolerations(tolerations []apiv1.Toleration) {
+	old := cfg.tolerationsString()
+	cfg.tolerations = tolerations
+	cfg.emit("tolerations", old, cfg.tolerationsString())
 }
 
 func (cfg *ServerConfig) GetCPU() resource.Quantity {
 	return cfg.cpu
 }
 
-func (cfg *ServerConfig) SetCPU(cpu string) {
-	if n, err := resource.ParseQuantity(cpu); err == nil {
-		cfg.cpu = n
+func (cfg *ServerConfig) SetCPU(cpu string) error {
+	n, err := resource.ParseQuantity(cpu)
+	if err != nil {
+		return fmt.Errorf("invalid cpu quantity %q: %w", cpu, err)
 	}
+	if cfg.policy != nil && !cfg.policy.CPU.Contains(n) {
+		return fmt.Errorf("cpu %s is outside the policy-allowed range", n.String())
+	}
+	old := cfg.cpu
+	cfg.cpu = n
+	cfg.emit("cpu", old.String(), cfg.cpu.String())
+	return nil
 }
 
 func (cfg *ServerConfig) GetRAM() resource.Quantity {
 	return cfg.ram
 }
 
-func (cfg *ServerConfig) SetRAM(ram string) {
-	if n, err := resource.ParseQuantity(ram + "Gi"); err == nil {
-		cfg.ram = n
+func (cfg *ServerConfig) SetRAM(ram string) error {
+	n, err := resource.ParseQuantity(ram + "Gi")
+	if err != nil {
+		return fmt.Errorf("invalid ram quantity %q: %w", ram, err)
+	}
+	if cfg.policy != nil && !cfg.policy.RAM.Contains(n) {
+		return fmt.Errorf("ram %s is outside the policy-allowed range", n.String())
 	}
+	old := cfg.ram
+	cfg.ram = n
+	cfg.emit("ram", old.String(), cfg.ram.String())
+	return nil
 }
 
 func (cfg *ServerConfig) GetDisk() resource.Quantity {
 	return cfg.disk
 }
 
-func (cfg *ServerConfig) SetDisk(disk string) {
-	if n, err := resource.ParseQuantity(disk + "Gi"); err == nil {
-		cfg.disk = n
+func (cfg *ServerConfig) SetDisk(disk string) error {
+	n, err := resource.ParseQuantity(disk + "Gi")
+	if err != nil {
+		return fmt.Errorf("invalid disk quantity %q: %w", disk, err)
+	}
+	if cfg.policy != nil && !cfg.policy.Disk.Contains(n) {
+		return fmt.Errorf("disk %s is outside the policy-allowed range", n.String())
 	}
+	old := cfg.disk
+	cfg.disk = n
+	cfg.emit("disk", old.String(), cfg.disk.String())
+	return nil
 }
 
-func (cfg *ServerConfig) GetKubeConfig() *kubernetes.Clientset {
+// GetVolumes returns the persistent volumes attached to cfg.
+func (cfg *ServerConfig) GetVolumes() []Volume {
+	return cfg.volumes
+}
+
+// AddVolume attaches a persistent volume claim of the given size,
+// StorageClass, access mode and mount path to cfg.
+func (cfg *ServerConfig) AddVolume(name string, size string, storageClass string, mode apiv1.PersistentVolumeAccessMode, mountPath string) error {
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return fmt.Errorf("invalid volume size %q: %w", size, err)
+	}
+	old := fmt.Sprintf("%d volumes", len(cfg.volumes))
+	cfg.volumes = append(cfg.volumes, Volume{
+		Name:         name,
+		Size:         q,
+		StorageClass: storageClass,
+		AccessMode:   mode,
+		MountPath:    mountPath,
+	})
+	cfg.emit("volumes", old, fmt.Sprintf("%d volumes (added %s)", len(cfg.volumes), name))
+	return nil
+}
+
+// DefaultStorageClass returns the name of the cluster's default
+// StorageClass, as indicated by the
+// "storageclass.kubernetes.io/is-default-class" annotation, or "" if no
+// StorageClass is marked default.
+func (cfg *ServerConfig) DefaultStorageClass() (string, error) {
+	classes, err := cfg.clientset.StorageV1().StorageClasses().List(cfg.ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("servconf: list storage classes: %w", err)
+	}
+	for _, sc := range classes.Items {
+		if sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return sc.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func (cfg *ServerConfig) GetKubeConfig() kubernetes.Interface {
 	return cfg.clientset
 }
 
+// Validate checks cfg against its active policy and returns a
+// ValidationErrors aggregating every violation found, or nil if cfg has no
+// policy or satisfies it in full.
+func (cfg *ServerConfig) Validate() error {
+	cfg.emitLifecycle("Validate")
+
+	if cfg.policy == nil {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if !cfg.policy.CPU.Contains(cfg.cpu) {
+		errs = append(errs, fmt.Errorf("cpu %s is outside the policy-allowed range", cfg.cpu.String()))
+	}
+	if !cfg.policy.RAM.Contains(cfg.ram) {
+		errs = append(errs, fmt.Errorf("ram %s is outside the policy-allowed range", cfg.ram.String()))
+	}
+	if !cfg.policy.Disk.Contains(cfg.disk) {
+		errs = append(errs, fmt.Errorf("disk %s is outside the policy-allowed range", cfg.disk.String()))
+	}
+	if !cfg.policy.AllowsServerType(cfg.serverType) {
+		errs = append(errs, fmt.Errorf("servertype %q is not permitted by policy", cfg.serverType))
+	}
+	if !cfg.policy.AllowsProtocol(cfg.protocol) {
+		errs = append(errs, fmt.Errorf("protocol %q is not permitted by policy", cfg.protocol))
+	}
+	if !cfg.policy.InternalPorts.Contains(cfg.internalPort) {
+		errs = append(errs, fmt.Errorf("internal port %d is outside the policy-allowed range", cfg.internalPort))
+	}
+	if !cfg.policy.ExternalPorts.Contains(cfg.externalPort) {
+		errs = append(errs, fmt.Errorf("external port %d is outside the policy-allowed range", cfg.externalPort))
+	}
+	if cfg.gpuCount > 0 && !cfg.policy.AllowsGPU() {
+		errs = append(errs, errors.New("gpu requests are not permitted by policy"))
+	}
+	if err := cfg.policy.CheckUserQuota(cfg.ctx, cfg.clientset, cfg.username); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// gpuString renders cfg's GPU request as "<count> x <resource name>", or ""
+// if no GPU was requested.
+func (cfg *ServerConfig) gpuString() string {
+	if cfg.gpuCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d x %s", cfg.gpuCount, cfg.GetGPUResourceName())
+}
+
+// nodeSelectorString renders cfg's node selector as a sorted, comma-separated
+// "key=value" list.
+func (cfg *ServerConfig) nodeSelectorString() string {
+	if len(cfg.nodeSelector) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(cfg.nodeSelector))
+	for k := range cfg.nodeSelector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", k, cfg.nodeSelector[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// tolerationsString renders cfg's tolerations as a comma-separated
+// "key=value:effect" list.
+func (cfg *ServerConfig) tolerationsString() string {
+	if len(cfg.tolerations) == 0 {
+		return ""
+	}
+	parts := make([]string, len(cfg.tolerations))
+	for i, t := range cfg.tolerations {
+		parts[i] = fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+	}
+	return strings.Join(parts, ",")
+}
+
+// webVolumes renders cfg's volumes in their web-facing form.
+func (cfg *ServerConfig) webVolumes() []WebVolume {
+	volumes := make([]WebVolume, len(cfg.volumes))
+	for i, v := range cfg.volumes {
+		volumes[i] = WebVolume{
+			Name:         v.Name,
+			Size:         v.Size.String(),
+			StorageClass: v.StorageClass,
+			AccessMode:   string(v.AccessMode),
+			MountPath:    v.MountPath,
+		}
+	}
+	return volumes
+}
+
 func (cfg *ServerConfig) WebConfig() *WebConfig {
+	cfg.emitLifecycle("WebConfig")
+
 	webconf := &WebConfig{
 		Username:     cfg.GetUsername(),
 		ServerName:   cfg.GetServerName(),
 		ServerType:   cfg.GetServerType(),
 		CPU:          cfg.GetCPU().OpenAPISchemaFormat(),
 		RAM:          cfg.GetRAM().OpenAPISchemaFormat(),
-		Disk:         cfg.GetRAM().OpenAPISchemaFormat(),
+		Disk:         cfg.GetDisk().OpenAPISchemaFormat(),
 		IP:           cfg.GetIP(),
 		InternalPort: cfg.GetInternalPort(),
 		ExternalPort: cfg.GetExternalPort(),
+		GPU:          cfg.gpuString(),
+		Arch:         cfg.GetArch(),
+		NodeSelector: cfg.nodeSelectorString(),
+		Tolerations:  cfg.tolerationsString(),
+		Volumes:      cfg.webVolumes(),
 	}
 	return webconf
 }
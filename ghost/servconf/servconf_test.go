@@ -0,0 +1,181 @@
+package servconf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Quinn-5/learning-go/ghost/servconf/policy"
+)
+
+func newTestConfig(t *testing.T) *ServerConfig {
+	t.Helper()
+	cfg, err := New("alice", "myserver", WithClientset(fake.NewSimpleClientset()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return cfg
+}
+
+func TestNewWithFakeClientset(t *testing.T) {
+	cfg := newTestConfig(t)
+	if cfg.GetUsername() != "alice" {
+		t.Fatalf("GetUsername() = %q, want %q", cfg.GetUsername(), "alice")
+	}
+}
+
+func TestSetCPURejectsOutOfRangePolicy(t *testing.T) {
+	cfg := newTestConfig(t)
+	maxCPU := resource.MustParse("2")
+	cfg.SetPolicy(&policy.Policy{CPU: policy.Range{Max: &maxCPU}})
+
+	if err := cfg.SetCPU("4"); err == nil {
+		t.Fatal("SetCPU(4) = nil, want a policy violation error")
+	}
+	if err := cfg.SetCPU("1"); err != nil {
+		t.Fatalf("SetCPU(1) = %v, want nil (within policy range)", err)
+	}
+}
+
+func TestSetGPURejectsWhenPolicyDisallows(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.SetPolicy(&policy.Policy{AllowGPU: false})
+
+	if err := cfg.SetGPU(1, "nvidia.com"); err == nil {
+		t.Fatal("SetGPU(1, \"nvidia.com\") = nil, want a policy violation error")
+	}
+
+	cfg.SetPolicy(&policy.Policy{AllowGPU: true})
+	if err := cfg.SetGPU(1, "nvidia.com"); err != nil {
+		t.Fatalf("SetGPU(1, \"nvidia.com\") = %v, want nil once policy allows gpus", err)
+	}
+}
+
+func TestValidateAggregatesViolations(t *testing.T) {
+	cfg := newTestConfig(t)
+	if err := cfg.SetCPU("4"); err != nil {
+		t.Fatalf("SetCPU: %v", err)
+	}
+	if err := cfg.SetType("game"); err != nil {
+		t.Fatalf("SetType: %v", err)
+	}
+
+	maxCPU := resource.MustParse("2")
+	cfg.SetPolicy(&policy.Policy{
+		CPU:                policy.Range{Max: &maxCPU},
+		AllowedServerTypes: []string{"web"},
+	})
+
+	err := cfg.Validate()
+	verrs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Validate() error type = %T, want ValidationErrors", err)
+	}
+	if len(verrs) != 2 {
+		t.Fatalf("len(ValidationErrors) = %d, want 2: %v", len(verrs), verrs)
+	}
+}
+
+func TestWatchDeliversAndClosesOnContextDone(t *testing.T) {
+	cfg := newTestConfig(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := cfg.Watch(ctx)
+
+	cfg.SetArch("amd64")
+
+	select {
+	case ev := <-ch:
+		if ev.Field != "arch" || ev.NewValue != "amd64" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for arch change event")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("Watch channel delivered a value instead of closing after ctx cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Watch channel to close")
+	}
+}
+
+func TestAddVolumeAndDefaultStorageClass(t *testing.T) {
+	defaultClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "standard",
+			Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+		},
+	}
+	cfg, err := New("alice", "myserver", WithClientset(fake.NewSimpleClientset(defaultClass)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	name, err := cfg.DefaultStorageClass()
+	if err != nil {
+		t.Fatalf("DefaultStorageClass: %v", err)
+	}
+	if name != "standard" {
+		t.Fatalf("DefaultStorageClass() = %q, want %q", name, "standard")
+	}
+
+	if err := cfg.AddVolume("data", "10Gi", name, apiv1.ReadWriteOnce, "/data"); err != nil {
+		t.Fatalf("AddVolume: %v", err)
+	}
+	volumes := cfg.GetVolumes()
+	if len(volumes) != 1 || volumes[0].Name != "data" {
+		t.Fatalf("GetVolumes() = %+v, want one volume named \"data\"", volumes)
+	}
+}
+
+func TestResolveRESTConfigPrefersExplicitPathOverKUBECONFIG(t *testing.T) {
+	explicitPath := filepath.Join(t.TempDir(), "explicit-kubeconfig")
+	if err := os.WriteFile(explicitPath, []byte(testKubeconfig("https://explicit.example.com")), 0o600); err != nil {
+		t.Fatalf("write explicit kubeconfig: %v", err)
+	}
+
+	envPath := filepath.Join(t.TempDir(), "env-kubeconfig")
+	if err := os.WriteFile(envPath, []byte(testKubeconfig("https://env.example.com")), 0o600); err != nil {
+		t.Fatalf("write $KUBECONFIG kubeconfig: %v", err)
+	}
+	t.Setenv("KUBECONFIG", envPath)
+
+	restConfig, err := resolveRESTConfig(explicitPath)
+	if err != nil {
+		t.Fatalf("resolveRESTConfig: %v", err)
+	}
+	if restConfig.Host != "https://explicit.example.com" {
+		t.Fatalf("restConfig.Host = %q, want the explicit path's host", restConfig.Host)
+	}
+}
+
+func testKubeconfig(server string) string {
+	return "apiVersion: v1\n" +
+		"kind: Config\n" +
+		"clusters:\n" +
+		"- cluster:\n" +
+		"    server: " + server + "\n" +
+		"  name: test\n" +
+		"contexts:\n" +
+		"- context:\n" +
+		"    cluster: test\n" +
+		"    user: test\n" +
+		"  name: test\n" +
+		"current-context: test\n" +
+		"users:\n" +
+		"- name: test\n" +
+		"  user: {}\n"
+}